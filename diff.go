@@ -0,0 +1,89 @@
+package snapper
+
+import "strings"
+
+// unifiedDiff renders a minimal line-based diff between want and got,
+// prefixing removed lines with "- ", added lines with "+ ", and unchanged
+// lines with two spaces, in the style of a unified diff without hunk
+// headers.
+func unifiedDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	ops := diffLines(wantLines, gotLines)
+
+	var b strings.Builder
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			b.WriteString("  " + op.line + "\n")
+		case diffRemove:
+			b.WriteString("- " + op.line + "\n")
+		case diffAdd:
+			b.WriteString("+ " + op.line + "\n")
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffRemove
+	diffAdd
+)
+
+type diffOp struct {
+	kind diffKind
+	line string
+}
+
+// diffLines computes a line-level diff using the standard LCS dynamic
+// program. Snapshot outputs are small enough that the O(n*m) table is
+// never a concern in practice.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{diffEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffRemove, a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffAdd, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffRemove, a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffAdd, b[j]})
+	}
+
+	return ops
+}
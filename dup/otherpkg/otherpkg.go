@@ -0,0 +1,9 @@
+// Package otherpkg shares its base import path segment with
+// github.com/gregoryjjb/snapper/otherpkg, on purpose - it exists to
+// exercise snapper's handling of colliding package identifiers.
+package otherpkg
+
+// Invoice is a sample type from a second, distinct "otherpkg".
+type Invoice struct {
+	Id int
+}
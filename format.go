@@ -0,0 +1,86 @@
+package snapper
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// formatExprName is the throwaway identifier the raw snapshot's final
+// expression is assigned to so the whole thing parses as a valid Go
+// file for go/format; it's stripped back out of the result afterwards.
+const formatExprName = "_snapperExpr"
+
+// formatSnap pipes raw - the output of renderRaw - through go/format,
+// preserving its shape: any hoisted "var snapN" declarations first, then
+// the main expression, gofmt-clean throughout.
+func formatSnap(raw string) (string, error) {
+	preamble, expr := splitPreamble(raw)
+
+	src := "package _snap\n\n" + preamble + "var " + formatExprName + " = " + expr + "\n"
+
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		return "", err
+	}
+
+	return extractFormattedExpr(formatted)
+}
+
+// extractFormattedExpr pulls the "var snapN = ..." preamble and the
+// formatExprName declaration's value back out of formatted, a fully
+// gofmt'd "package _snap" file. It locates the declaration by parsing
+// formatted and slicing by AST position rather than by searching for the
+// "var _snapperExpr = " marker as text, since that marker can also occur
+// verbatim inside a rendered string field's content, which would corrupt
+// both the split point and the user's data.
+func extractFormattedExpr(formatted []byte) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", formatted, 0)
+	if err != nil {
+		return "", err
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok || len(vs.Values) != 1 {
+				continue
+			}
+			if len(vs.Names) != 1 || vs.Names[0].Name != formatExprName {
+				continue
+			}
+
+			declStart := fset.Position(gen.Pos()).Offset
+			exprStart := fset.Position(vs.Values[0].Pos()).Offset
+			exprEnd := fset.Position(vs.Values[0].End()).Offset
+
+			preamble := strings.TrimPrefix(string(formatted[:declStart]), "package _snap\n")
+			preamble = strings.TrimLeft(preamble, "\n")
+
+			return preamble + string(formatted[exprStart:exprEnd]), nil
+		}
+	}
+
+	return "", fmt.Errorf("snapper: formatted output has no %s declaration", formatExprName)
+}
+
+// splitPreamble separates renderRaw's output into its leading
+// "var snapN = ..." declarations (if any) and the final expression, which
+// is everything after the last blank line.
+func splitPreamble(raw string) (preamble, expr string) {
+	idx := strings.LastIndex(raw, "\n\n")
+	if idx == -1 {
+		return "", raw
+	}
+
+	return raw[:idx+2], raw[idx+2:]
+}
@@ -0,0 +1,73 @@
+package snapper_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gregoryjjb/snapper"
+)
+
+func TestSsnap_DefaultsToGoFormatted(t *testing.T) {
+	type Wide struct {
+		A    int
+		Long string
+	}
+
+	// Field names of different lengths make gofmt align the values, so
+	// the default-formatted and raw outputs differ here; this guards
+	// against the default silently flipping back to raw.
+	formatted := snapper.Ssnap(Wide{A: 1, Long: "x"})
+	raw := snapper.Ssnap(Wide{A: 1, Long: "x"}, snapper.WithGoFormat(false))
+
+	assert.Equal(t, "Wide{\n\tA:    1,\n\tLong: \"x\",\n}", formatted)
+	assert.NotEqual(t, raw, formatted)
+}
+
+func TestFsnap_DefaultsToRaw(t *testing.T) {
+	type Wide struct {
+		A    int
+		Long int
+	}
+
+	var buf bytes.Buffer
+	snapper.Fsnap(&buf, Wide{A: 1, Long: 2})
+
+	// Raw output keeps a single space after each field name, where
+	// gofmt would align the values.
+	assert.Equal(t, "Wide{\n\tA: 1,\n\tLong: 2,\n}", buf.String())
+}
+
+func TestFsnap_WithGoFormatTrue(t *testing.T) {
+	type Wide struct {
+		A int
+	}
+
+	var buf bytes.Buffer
+	snapper.Fsnap(&buf, Wide{A: 1}, snapper.WithGoFormat(true))
+
+	assert.Equal(t, "Wide{\n\tA: 1,\n}", buf.String())
+}
+
+func TestSsnap_GoFormatHandlesMarkerLookalikeInStringField(t *testing.T) {
+	type Tricky struct {
+		Field string
+	}
+
+	actual := snapper.Ssnap(Tricky{Field: "var _snapperExpr = oops"})
+
+	assert.Equal(t, "Tricky{\n\tField: \"var _snapperExpr = oops\",\n}", actual)
+}
+
+func TestSsnap_GoFormatFallsBackOnUnparsableOutput(t *testing.T) {
+	n := &LinkedNode{Value: 1}
+	n.Next = n
+
+	actual := snapper.Ssnap(n)
+
+	require.True(t, strings.HasPrefix(actual, "// snapper: go/format failed:"), "expected a recorded format error, got %q", actual)
+	assert.Contains(t, actual, "var snap1 = &LinkedNode{")
+}
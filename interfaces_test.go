@@ -0,0 +1,46 @@
+package snapper_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryjjb/snapper"
+)
+
+func TestSsnap_HeterogeneousAnySlice(t *testing.T) {
+	actual := snapper.Ssnap([]any{1, "x", SampleStruct{Bar: "y", Baz: 2}})
+
+	expect := `[]any{
+	any(1),
+	any("x"),
+	any(SampleStruct{
+		Bar: "y",
+		Baz: 2,
+	}),
+}`
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestSsnap_NilInterfaceElement(t *testing.T) {
+	actual := snapper.Ssnap([]any{1, nil})
+
+	expect := `[]any{
+	any(1),
+	nil,
+}`
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestSsnap_MapWithAnyValues(t *testing.T) {
+	actual := snapper.Ssnap(map[string]any{"b": 2, "a": "x"})
+
+	expect := `map[string]any{
+	"a": any("x"),
+	"b": any(2),
+}`
+
+	assert.Equal(t, expect, actual)
+}
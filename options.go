@@ -0,0 +1,62 @@
+package snapper
+
+import "reflect"
+
+// config holds the resolved settings for a single render or MatchSnapshot
+// call.
+type config struct {
+	name      string
+	goFormat  bool
+	renderers map[reflect.Type]func(any) string
+	pkgAlias  map[string]string
+}
+
+func (c *config) setRenderer(t reflect.Type, render func(any) string) {
+	if c.renderers == nil {
+		c.renderers = map[reflect.Type]func(any) string{}
+	}
+	c.renderers[t] = render
+}
+
+// Option customizes the behavior of Snap, Ssnap, Fsnap, and MatchSnapshot.
+type Option func(*config)
+
+// WithName gives a snapshot an explicit name instead of the default
+// call-order index. Use it when a test calls MatchSnapshot from a loop or
+// conditionally, where call order alone wouldn't be stable across runs.
+func WithName(name string) Option {
+	return func(c *config) {
+		c.name = name
+	}
+}
+
+// WithGoFormat controls whether output is piped through go/format before
+// being returned. It's on by default for Snap and Ssnap, and off by
+// default for Fsnap; pass WithGoFormat(false) to render the raw,
+// hand-indented output instead, or WithGoFormat(true) to format an Fsnap
+// call.
+func WithGoFormat(enabled bool) Option {
+	return func(c *config) {
+		c.goFormat = enabled
+	}
+}
+
+// WithPkgAlias overrides the automatically inferred package aliasing for
+// a single call. Keys are a package's bare identifier as it appears in a
+// rendered type name (e.g. "otherpkg"); values are the alias to print it
+// under, or "" to print that package's types unqualified.
+func WithPkgAlias(alias map[string]string) Option {
+	return func(c *config) {
+		c.pkgAlias = alias
+	}
+}
+
+// WithRenderer overrides how values of type T are rendered for a single
+// call, taking precedence over both the default reflect-based rendering
+// and any renderer installed globally with Register.
+func WithRenderer[T any](render func(T) string) Option {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	return func(c *config) {
+		c.setRenderer(t, func(v any) string { return render(v.(T)) })
+	}
+}
@@ -0,0 +1,14 @@
+// Package otherpkg provides sample types used to exercise snapper's handling
+// of structs defined outside the calling package.
+package otherpkg
+
+// Order represents a single line item belonging to a User.
+type Order struct {
+	Id int
+}
+
+// User is a sample struct from a foreign package.
+type User struct {
+	Name   string
+	Orders []Order
+}
@@ -0,0 +1,195 @@
+package snapper
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// inferPkgAlias builds a pkgAlias map automatically, the same shape Snap,
+// Ssnap, and Fsnap previously required callers to build by hand: the
+// caller's own package (found by walking skip frames up the stack from
+// inferPkgAlias's own caller) maps to "" so its type names print
+// unqualified, and every other
+// package whose types appear in i keeps its own name unless that name
+// collides with another package also present in i, in which case the
+// later one (by import path, sorted) gets a numeric suffix (pkgb2,
+// pkgb3, ...) - the same fallback go/printer and the Kubernetes raw
+// namer use.
+//
+// The map is keyed by import path rather than by the bare identifier
+// reflect.Type.String() prints, which is what makes the suffix usable:
+// qualifiedTypeName looks a type's alias up by its own t.PkgPath(), so
+// two distinct import paths that happen to share a last segment (e.g.
+// both named "otherpkg") each get their own, correctly-scoped entry
+// instead of a single textual substitution rule being forced to treat
+// them the same.
+func inferPkgAlias(i any, skip int) map[string]string {
+	alias := map[string]string{}     // import path -> alias ("" means print unqualified)
+	claimedBy := map[string]string{} // short identifier -> import path that has claimed it as-is
+
+	callerPath, ok := callerImportPath(skip + 2)
+	if ok {
+		claimedBy[shortPkgName(callerPath)] = callerPath
+		alias[callerPath] = ""
+	}
+
+	var paths []string
+	for path := range referencedPackages(i) {
+		if path == callerPath {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		short := shortPkgName(path)
+		if _, taken := claimedBy[short]; !taken {
+			claimedBy[short] = path
+			continue
+		}
+
+		for n := 2; ; n++ {
+			candidate := fmt.Sprintf("%s%d", short, n)
+			if _, taken := claimedBy[candidate]; !taken {
+				claimedBy[candidate] = path
+				alias[path] = candidate
+				break
+			}
+		}
+	}
+
+	return alias
+}
+
+// qualifiedTypeName renders t's type name the way it should appear in a
+// snapshot, qualifying named types against pathAlias by their own
+// t.PkgPath() rather than doing a textual substitution on t.String().
+// That per-type lookup is what lets inferPkgAlias's numeric suffixes
+// actually disambiguate two distinct import paths that share a short
+// name: each occurrence is qualified from the type that produced it, so
+// there's no shared piece of text for the two packages to collide over.
+func qualifiedTypeName(t reflect.Type, pathAlias map[string]string) string {
+	if t.Name() == "" {
+		switch t.Kind() {
+		case reflect.Pointer:
+			return "*" + qualifiedTypeName(t.Elem(), pathAlias)
+		case reflect.Slice:
+			return "[]" + qualifiedTypeName(t.Elem(), pathAlias)
+		case reflect.Array:
+			return fmt.Sprintf("[%d]%s", t.Len(), qualifiedTypeName(t.Elem(), pathAlias))
+		case reflect.Map:
+			return fmt.Sprintf("map[%s]%s", qualifiedTypeName(t.Key(), pathAlias), qualifiedTypeName(t.Elem(), pathAlias))
+		}
+	}
+
+	path := t.PkgPath()
+	if path == "" {
+		return t.String()
+	}
+
+	alias, ok := pathAlias[path]
+	if !ok {
+		alias = shortPkgName(path)
+	}
+
+	if alias == "" {
+		return t.Name()
+	}
+
+	return alias + "." + t.Name()
+}
+
+// callerImportPath returns the import path of the package skip frames up
+// the stack, using the same "skip=0 is this function's own frame"
+// convention as runtime.Caller.
+func callerImportPath(skip int) (string, bool) {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "", false
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "", false
+	}
+
+	full := fn.Name() // e.g. "github.com/gregoryjjb/snapper_test.TestFoo"
+	slash := strings.LastIndex(full, "/")
+	rest := full[slash+1:]
+	dot := strings.Index(rest, ".")
+	if dot == -1 {
+		return "", false
+	}
+
+	return full[:slash+1] + rest[:dot], true
+}
+
+// shortPkgName returns the identifier a package's types print under,
+// i.e. the last segment of its import path.
+func shortPkgName(path string) string {
+	if i := strings.LastIndex(path, "/"); i != -1 {
+		return path[i+1:]
+	}
+	return path
+}
+
+// referencedPackages walks i and returns the import paths of every named
+// type reachable from it.
+func referencedPackages(i any) map[string]bool {
+	paths := map[string]bool{}
+	walkPkgPaths(reflect.ValueOf(i), paths, map[uintptr]bool{})
+	return paths
+}
+
+func walkPkgPaths(v reflect.Value, paths map[string]bool, active map[uintptr]bool) {
+	if !v.IsValid() {
+		return
+	}
+
+	if path := v.Type().PkgPath(); path != "" {
+		paths[path] = true
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+
+		addr := v.Pointer()
+		if active[addr] {
+			return
+		}
+
+		active[addr] = true
+		walkPkgPaths(v.Elem(), paths, active)
+		active[addr] = false
+
+	case reflect.Struct:
+		t := v.Type()
+		for i, field := range reflect.VisibleFields(t) {
+			if !field.IsExported() {
+				continue
+			}
+			walkPkgPaths(v.Field(i), paths, active)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkPkgPaths(v.Index(i), paths, active)
+		}
+
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			walkPkgPaths(key, paths, active)
+			walkPkgPaths(v.MapIndex(key), paths, active)
+		}
+
+	case reflect.Interface:
+		walkPkgPaths(v.Elem(), paths, active)
+	}
+}
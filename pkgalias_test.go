@@ -0,0 +1,54 @@
+package snapper_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryjjb/snapper"
+	dupotherpkg "github.com/gregoryjjb/snapper/dup/otherpkg"
+	"github.com/gregoryjjb/snapper/otherpkg"
+)
+
+func TestSsnap_AutoAliasesCallingPackage(t *testing.T) {
+	actual := snapper.Ssnap(SampleStruct{Bar: "x", Baz: 1})
+
+	assert.Equal(t, "SampleStruct{\n\tBar: \"x\",\n\tBaz: 1,\n}", actual)
+}
+
+func TestSsnap_AutoKeepsForeignPackageName(t *testing.T) {
+	actual := snapper.Ssnap(otherpkg.Order{Id: 1})
+
+	assert.Equal(t, "otherpkg.Order{\n\tId: 1,\n}", actual)
+}
+
+func TestSsnap_CollidingPackageNamesGetNumericSuffix(t *testing.T) {
+	type Bundle struct {
+		Order   otherpkg.Order
+		Invoice dupotherpkg.Invoice
+	}
+
+	actual := snapper.Ssnap(Bundle{Order: otherpkg.Order{Id: 1}, Invoice: dupotherpkg.Invoice{Id: 2}})
+
+	// dup/otherpkg sorts before otherpkg, so it claims the bare "otherpkg"
+	// identifier first; otherpkg collides and is pushed to "otherpkg2".
+	expect := `Bundle{
+	Order: otherpkg2.Order{
+		Id: 1,
+	},
+	Invoice: otherpkg.Invoice{
+		Id: 2,
+	},
+}`
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestWithPkgAlias_OverridesInference(t *testing.T) {
+	actual := snapper.Ssnap(
+		otherpkg.Order{Id: 1},
+		snapper.WithPkgAlias(map[string]string{"otherpkg": "op"}),
+	)
+
+	assert.Equal(t, "op.Order{\n\tId: 1,\n}", actual)
+}
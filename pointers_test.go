@@ -0,0 +1,87 @@
+package snapper_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryjjb/snapper"
+)
+
+type LinkedNode struct {
+	Value int
+	Next  *LinkedNode
+}
+
+type TreeNode struct {
+	Value  int
+	Parent *TreeNode
+}
+
+func TestSnap_SelfReferentialStruct(t *testing.T) {
+	n := &LinkedNode{Value: 1}
+	n.Next = n
+
+	actual := snapper.Ssnap(n, snapper.WithGoFormat(false))
+
+	expect := `var snap1 = &LinkedNode{
+	Value: 1,
+	Next: /* cycle: &LinkedNode#1 */,
+}
+
+snap1`
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestSnap_MutualRecursion(t *testing.T) {
+	parent := &TreeNode{Value: 1}
+	child := &TreeNode{Value: 2, Parent: parent}
+	parent.Parent = child
+
+	actual := snapper.Ssnap(parent, snapper.WithGoFormat(false))
+
+	expect := `var snap1 = &TreeNode{
+	Value: 1,
+	Parent: &TreeNode{
+		Value: 2,
+		Parent: /* cycle: &TreeNode#1 */,
+	},
+}
+
+snap1`
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestSnap_NilPointerField(t *testing.T) {
+	n := LinkedNode{Value: 1, Next: nil}
+
+	actual := snapper.Ssnap(n, snapper.WithGoFormat(false))
+
+	expect := `LinkedNode{
+	Value: 1,
+	Next: nil,
+}`
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestSnap_SlicePointerAppearingTwice(t *testing.T) {
+	shared := &SampleStruct{Bar: "shared", Baz: 1}
+	slice := []*SampleStruct{shared, shared}
+
+	actual := snapper.Ssnap(slice)
+
+	expect := `var snap1 = &SampleStruct{
+	Bar: "shared",
+	Baz: 1,
+}
+
+[]*SampleStruct{
+	snap1,
+	snap1,
+}`
+
+	assert.Equal(t, expect, actual)
+}
@@ -0,0 +1,101 @@
+package snapper
+
+import "reflect"
+
+// refScanner walks a value ahead of rendering to find pointers that are
+// reachable from more than one place, so Fsnap can hoist them into a
+// shared `var snapN` instead of printing them (and losing their shared
+// identity) at every use site.
+type refScanner struct {
+	counts map[uintptr]int
+	order  []uintptr
+	index  map[uintptr]int // addr -> snapN, only set for addrs with counts > 1
+	values map[uintptr]reflect.Value
+	active map[uintptr]bool
+}
+
+// scanRefs walks i and returns the pointers within it that are
+// referenced from more than one place, numbered in the order they were
+// first encountered.
+func scanRefs(i any) *refScanner {
+	rs := &refScanner{
+		counts: map[uintptr]int{},
+		index:  map[uintptr]int{},
+		values: map[uintptr]reflect.Value{},
+		active: map[uintptr]bool{},
+	}
+
+	rs.walk(reflect.ValueOf(i))
+
+	n := 0
+	for _, addr := range rs.order {
+		if rs.counts[addr] > 1 {
+			n++
+			rs.index[addr] = n
+		}
+	}
+
+	return rs
+}
+
+func (rs *refScanner) walk(v reflect.Value) {
+	if !v.IsValid() {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+
+		addr := v.Pointer()
+
+		if rs.active[addr] {
+			// A back-edge to a pointer we're already in the middle of
+			// walking: a genuine cycle. Count it and stop, rather than
+			// recursing forever.
+			rs.counts[addr]++
+			return
+		}
+
+		if rs.counts[addr] == 0 {
+			rs.order = append(rs.order, addr)
+			rs.values[addr] = v
+		}
+		rs.counts[addr]++
+
+		if rs.counts[addr] > 1 {
+			// Already walked from an earlier visit; walking again would
+			// just double-count its descendants.
+			return
+		}
+
+		rs.active[addr] = true
+		rs.walk(v.Elem())
+		rs.active[addr] = false
+
+	case reflect.Struct:
+		t := v.Type()
+		for i, field := range reflect.VisibleFields(t) {
+			if !field.IsExported() {
+				continue
+			}
+			rs.walk(v.Field(i))
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			rs.walk(v.Index(i))
+		}
+
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			rs.walk(key)
+			rs.walk(v.MapIndex(key))
+		}
+
+	case reflect.Interface:
+		rs.walk(v.Elem())
+	}
+}
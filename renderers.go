@@ -0,0 +1,44 @@
+package snapper
+
+import (
+	"reflect"
+	"sync"
+)
+
+// renderersMu guards renderers, the process-wide registry populated by
+// Register.
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[reflect.Type]func(any) string{}
+)
+
+// Register installs a renderer for type T that every future Snap, Ssnap,
+// Fsnap, and MatchSnapshot call will use instead of the default
+// reflect-based rendering. It's meant to be called once, typically from
+// an init function, for types whose zero-value-oriented struct layout
+// renders as noisy or lossy Go (time.Time, *big.Int, a UUID, ...). Use
+// WithRenderer for a renderer scoped to a single call instead.
+func Register[T any](render func(T) string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[t] = func(v any) string { return render(v.(T)) }
+}
+
+// mergedRenderers combines the global registry with any per-call
+// overrides from cfg, with the per-call ones taking precedence.
+func mergedRenderers(cfg *config) map[reflect.Type]func(any) string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	merged := make(map[reflect.Type]func(any) string, len(renderers)+len(cfg.renderers))
+	for t, render := range renderers {
+		merged[t] = render
+	}
+	for t, render := range cfg.renderers {
+		merged[t] = render
+	}
+
+	return merged
+}
@@ -0,0 +1,89 @@
+package snapper_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gregoryjjb/snapper"
+)
+
+type Celsius float64
+
+func TestRegister_UsedByDefault(t *testing.T) {
+	snapper.Register(func(c Celsius) string {
+		return fmt.Sprintf("%gC", float64(c))
+	})
+
+	actual := snapper.Ssnap(Celsius(100), snapper.WithGoFormat(false))
+
+	assert.Equal(t, "100C", actual)
+}
+
+func TestWithRenderer_OverridesGlobalAndDefault(t *testing.T) {
+	type Wrapped struct {
+		Inner Celsius
+	}
+
+	snapper.Register(func(c Celsius) string { return "global" })
+
+	actual := snapper.Ssnap(
+		Wrapped{Inner: Celsius(20)},
+		snapper.WithRenderer(func(c Celsius) string { return "overridden" }),
+	)
+
+	assert.Equal(t, "Wrapped{\n\tInner: overridden,\n}", actual)
+}
+
+func TestWithStdRenderers_Time(t *testing.T) {
+	at := time.Date(2024, 3, 15, 10, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	actual := snapper.Ssnap(at, snapper.WithStdRenderers())
+
+	assert.Equal(t, "time.Date(2024, 3, 15, 15, 30, 0, 0, time.UTC)", actual)
+}
+
+func TestWithStdRenderers_Duration(t *testing.T) {
+	cases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{time.Second, "time.Second"},
+		{5 * time.Second, "5 * time.Second"},
+		{90 * time.Minute, "90 * time.Minute"},
+		{1500 * time.Microsecond, "1500 * time.Microsecond"},
+	}
+
+	for _, c := range cases {
+		actual := snapper.Ssnap(c.d, snapper.WithStdRenderers())
+		assert.Equal(t, c.want, actual)
+	}
+}
+
+func TestWithStdRenderers_BigInt(t *testing.T) {
+	actual := snapper.Ssnap(big.NewInt(42), snapper.WithStdRenderers())
+	assert.Equal(t, "big.NewInt(42)", actual)
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	actual = snapper.Ssnap(huge, snapper.WithStdRenderers())
+	assert.Equal(t, `func() *big.Int { n, _ := new(big.Int).SetString("123456789012345678901234567890", 10); return n }()`, actual)
+}
+
+func TestWithStdRenderers_BigRat(t *testing.T) {
+	actual := snapper.Ssnap(big.NewRat(1, 3), snapper.WithStdRenderers())
+	assert.Equal(t, "big.NewRat(1, 3)", actual)
+}
+
+func TestWithStdRenderers_NilBigIntAndBigRat(t *testing.T) {
+	type Balance struct {
+		Amount *big.Int
+		Rate   *big.Rat
+	}
+
+	actual := snapper.Ssnap(Balance{}, snapper.WithStdRenderers(), snapper.WithGoFormat(false))
+
+	assert.Equal(t, "Balance{\n\tAmount: nil,\n\tRate: nil,\n}", actual)
+}
@@ -8,6 +8,8 @@ Snapper supports the following types:
 	* Structs
 	* Pointers to structs
 	* Arrays/slices
+	* Maps, with keys always emitted in a stable order regardless of Go's randomized iteration
+	* Interfaces, unwrapped to their concrete value and wrapped in an explicit conversion
 
 It does NOT support:
 
@@ -16,9 +18,6 @@ It does NOT support:
 	* Private fields in structs (they will be skipped)
 	* Channels
 
-Note that pointers to the same struct will be represented as two separately instantiated
-structs.
-
 # Example
 
 	snapper.Snap(thing) // Print to stdout
@@ -31,6 +30,52 @@ When writing a test case for a function that returns a big result (say, a slice
 many fields) it's tedious to type out the entire test case. With snapper you can run your function,
 print out a snapshot of the result, ensure it's correct, then copy and paste the snapshot directly
 into your test file.
+
+# Package names
+
+Snap, Ssnap, and Fsnap infer package qualification automatically: the calling package prints
+unqualified, and every other package whose types appear in the value keeps its own name (with a
+numeric suffix if two different packages happen to share one). Pass WithPkgAlias to override this
+with specific names instead.
+
+# Shared and cyclic pointers
+
+If the same pointer is reachable from more than one place in the value (a shared node, a doubly
+linked list, a tree with parent pointers), snapper hoists it into a "var snapN = &T{...}" block
+printed ahead of the main snapshot, with every use site referring back to snapN so the rendered
+identity matches the original. A pointer that refers back to one of its own ancestors - a true
+cycle - is rendered as a "cycle: &T#N" comment placeholder instead of recursing forever.
+
+# Formatting
+
+Snap and Ssnap pipe their output through go/format by default, so it's always gofmt-clean and
+safe to paste straight into a source file. Fsnap leaves output raw by default. Pass WithGoFormat(true) or
+WithGoFormat(false) to either to override the default. If the rendered value can't be parsed as
+Go (for example a cycle placeholder sitting where a field value belongs), formatting falls back
+to the raw output with the go/format error recorded in a leading comment.
+
+# Snapshot testing
+
+For the common case of "does this still render the way it did last time", skip the copy-paste
+step entirely with MatchSnapshot, which stores and compares snapshots on disk the way frontend
+snapshot tests do:
+
+	func TestThing(t *testing.T) {
+		snapper.MatchSnapshot(t, computeThing())
+	}
+
+The first run writes testdata/__snapshots__/TestThing.snap; later runs compare against it and
+fail with a diff on mismatch. Run with `go test -update`, or set UPDATE_SNAPSHOTS=1, to rewrite
+snapshots instead of checking them.
+
+# Custom renderers
+
+Some types don't round-trip usefully through the default struct rendering because their
+interesting state lives in unexported fields (time.Time, math/big.Int, a UUID, a protobuf
+message). Register installs a renderer for a type that every future call uses in place of the
+default rendering; WithRenderer does the same for a single call, taking precedence over both
+Register and the default. WithStdRenderers() opts a call into snapper's built-in renderers for
+time.Time, time.Duration, *big.Int, and *big.Rat.
 */
 package snapper
 
@@ -40,6 +85,7 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -47,20 +93,73 @@ import (
 // UseAny instead of interface{}
 const UseAny = true
 
-// Snap outputs a snapshot of i to stdout
-func Snap(i any, pkgAlias map[string]string) {
-	Fsnap(os.Stdout, i, pkgAlias)
+// Snap outputs a snapshot of i to stdout. Package names are inferred
+// automatically from the caller and from i's own types; pass
+// WithPkgAlias to override them. Output is gofmt-formatted by default;
+// pass WithGoFormat(false) to get the raw, hand-indented form.
+func Snap(i any, opts ...Option) {
+	io.WriteString(os.Stdout, render(i, true, 1, opts))
+}
+
+// Ssnap returns a string of a snapshot of i. Package names are inferred
+// automatically from the caller and from i's own types; pass
+// WithPkgAlias to override them. Output is gofmt-formatted by default;
+// pass WithGoFormat(false) to get the raw, hand-indented form.
+func Ssnap(i any, opts ...Option) string {
+	return render(i, true, 1, opts)
 }
 
-// Ssnap returns a string of a snapshot of i
-func Ssnap(i any, pkgAlias map[string]string) string {
-	buf := new(bytes.Buffer)
-	Fsnap(buf, i, pkgAlias)
-	return buf.String()
+// Fsnap outputs a snapshot of i to the provided writer. Package names are
+// inferred automatically from the caller and from i's own types; pass
+// WithPkgAlias to override them. Unlike Snap and Ssnap, output is raw and
+// hand-indented by default; pass WithGoFormat(true) to pipe it through
+// go/format instead.
+func Fsnap(w io.Writer, i any, opts ...Option) {
+	io.WriteString(w, render(i, false, 1, opts))
 }
 
-// Fsnap outputs a snapshot of i to the provided writer
-func Fsnap(w io.Writer, i any, pkgAlias map[string]string) {
+// render builds a snapshot of i and returns it as a string, applying
+// defaultGoFormat unless opts overrides it with WithGoFormat. callerSkip
+// is how many frames render's own caller sits above the original call
+// site, used to infer pkgAlias when opts doesn't override it with
+// WithPkgAlias.
+func render(i any, defaultGoFormat bool, callerSkip int, opts []Option) string {
+	cfg := &config{goFormat: defaultGoFormat}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var typeName func(reflect.Type) string
+	if cfg.pkgAlias != nil {
+		typeName = shortNameTypeName(cfg.pkgAlias)
+	} else {
+		pathAlias := inferPkgAlias(i, callerSkip+1)
+		typeName = func(t reflect.Type) string { return qualifiedTypeName(t, pathAlias) }
+	}
+
+	raw := renderRaw(i, typeName, mergedRenderers(cfg))
+	if !cfg.goFormat {
+		return raw
+	}
+
+	formatted, err := formatSnap(raw)
+	if err != nil {
+		return fmt.Sprintf("// snapper: go/format failed: %s; showing unformatted output\n%s", err, raw)
+	}
+
+	return formatted
+}
+
+// shortNameTypeName builds a type-naming function from an explicit,
+// short-name-keyed pkgAlias override (see WithPkgAlias): it substitutes
+// the package identifier in t.String() with a flat text replace, the
+// same mechanism Snap used before automatic inference existed. That's
+// fine for the common case of renaming one specific package by name; it
+// can't tell two distinctly-imported packages with the same short name
+// apart, since all that's known here is the name the caller asked to
+// rewrite, not an import path - callers who need that should let
+// inference run instead of overriding it.
+func shortNameTypeName(pkgAlias map[string]string) func(reflect.Type) string {
 	var patterns []string
 	for old, new := range pkgAlias {
 		if new == "" {
@@ -69,42 +168,109 @@ func Fsnap(w io.Writer, i any, pkgAlias map[string]string) {
 
 		patterns = append(patterns, old, new)
 	}
+	replacer := strings.NewReplacer(patterns...)
+
+	return func(t reflect.Type) string {
+		return replacer.Replace(t.String())
+	}
+}
 
+// renderRaw builds the hand-indented snapshot of i, including any shared
+// or cyclic pointers hoisted into a preamble of var declarations.
+func renderRaw(i any, typeName func(reflect.Type) string, renderers map[reflect.Type]func(any) string) string {
 	s := &snapper{
-		w:            w,
-		typeReplacer: strings.NewReplacer(patterns...),
+		buf:       new(bytes.Buffer),
+		typeName:  typeName,
+		renderers: renderers,
+	}
+
+	shared := scanRefs(i)
+	s.shared = shared.index
+
+	var preamble bytes.Buffer
+	for _, addr := range shared.order {
+		n, ok := shared.index[addr]
+		if !ok {
+			continue
+		}
+
+		s.buf = new(bytes.Buffer)
+		s.defining = addr
+		s.write("&")
+		s.snap(shared.values[addr].Elem(), 0, false)
+		s.defining = 0
+
+		fmt.Fprintf(&preamble, "var snap%d = %s\n\n", n, s.buf.String())
 	}
 
-	s.snap(i, 0, false)
+	s.buf = new(bytes.Buffer)
+	s.snap(reflect.ValueOf(i), 0, false)
+
+	return preamble.String() + s.buf.String()
 }
 
 type snapper struct {
-	w            io.Writer
-	typeReplacer *strings.Replacer
+	buf      *bytes.Buffer
+	typeName func(reflect.Type) string // builds a (possibly package-qualified) name for a type
+
+	shared   map[uintptr]int // addr -> snapN index, for pointers referenced from more than one place
+	defining uintptr         // addr whose var body is currently being rendered, 0 if none
+
+	renderers map[reflect.Type]func(any) string // custom renderers, consulted before the default dispatch
 }
 
 func (s *snapper) write(str string) {
-	io.WriteString(s.w, str)
+	io.WriteString(s.buf, str)
+}
+
+// nameOf renders t's type name as it should appear in the snapshot,
+// qualified (or not) by s.typeName and cleaned of the raw "interface {}"
+// spelling.
+func (s *snapper) nameOf(t reflect.Type) string {
+	return cleanEmptyInterface(s.typeName(t), UseAny)
 }
 
-func (s *snapper) snap(i any, indent int, omitStructName bool) {
-	if i == nil {
+func (s *snapper) snap(v reflect.Value, indent int, omitStructName bool) {
+	if !v.IsValid() {
 		s.write("nil")
 		return
 	}
 
+	// Interfaces (e.g. an element of []any, or a map with an interface
+	// key/value type): unwrap to the concrete value and wrap it in an
+	// explicit conversion so the snippet keeps its dynamic type instead
+	// of the Go compiler inferring a default one from the literal alone.
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			s.write("nil")
+			return
+		}
+
+		name := s.nameOf(v.Type())
+
+		s.write(name + "(")
+		s.snap(v.Elem(), indent, false)
+		s.write(")")
+		return
+	}
+
+	if custom, ok := s.renderers[v.Type()]; ok {
+		s.write(custom(v.Interface()))
+		return
+	}
+
 	// Easy literals that can just be printed
-	switch v := i.(type) {
+	switch val := v.Interface().(type) {
 	case
 		int, int8, int16, int32, int64,
 		uint, uint8, uint16, uint32, uint64,
 		float32, float64,
 		bool:
-		fmt.Fprintf(s.w, "%v", v)
+		fmt.Fprintf(s.buf, "%v", val)
 		return
 
 	case string:
-		s.write(strconv.Quote(v))
+		s.write(strconv.Quote(val))
 		return
 	}
 
@@ -114,7 +280,6 @@ func (s *snapper) snap(i any, indent int, omitStructName bool) {
 	}
 	innerTabs := baseTabs + "\t"
 
-	v := reflect.ValueOf(i)
 	switch v.Kind() {
 
 	// Structs
@@ -122,10 +287,7 @@ func (s *snapper) snap(i any, indent int, omitStructName bool) {
 		t := v.Type()
 
 		if !omitStructName {
-			name := t.String()
-			name = s.typeReplacer.Replace(name)
-
-			s.write(name)
+			s.write(s.nameOf(t))
 		}
 		s.write("{")
 
@@ -136,34 +298,28 @@ func (s *snapper) snap(i any, indent int, omitStructName bool) {
 			}
 
 			name := t.Field(i).Name
-			value := v.Field(i).Interface()
 
 			// if printedSoFar > 0 {
 			// 	fmt.Fprint(w, ", ")
 			// }
 
-			fmt.Fprintf(s.w, "\n%s%s: ", innerTabs, name)
-			s.snap(value, indent+1, false)
+			fmt.Fprintf(s.buf, "\n%s%s: ", innerTabs, name)
+			s.snap(v.Field(i), indent+1, false)
 			s.write(",")
 			printedSoFar += 1
 		}
 
-		fmt.Fprintf(s.w, "\n%s}", baseTabs)
+		fmt.Fprintf(s.buf, "\n%s}", baseTabs)
 
 	// Slices
 	case reflect.Slice, reflect.Array:
 		t := v.Type()
 
-		name := t.String()
-		name = cleanEmptyInterface(name, UseAny)
-		name = s.typeReplacer.Replace(name)
-
-		s.write(name + "{")
+		s.write(s.nameOf(t) + "{")
 
 		for i := 0; i < v.Len(); i++ {
-			element := v.Index(i).Interface()
-			fmt.Fprintf(s.w, "\n%s", innerTabs)
-			s.snap(element, indent+1, true)
+			fmt.Fprintf(s.buf, "\n%s", innerTabs)
+			s.snap(v.Index(i), indent+1, true)
 			s.write(",")
 		}
 
@@ -177,19 +333,19 @@ func (s *snapper) snap(i any, indent int, omitStructName bool) {
 	case reflect.Map:
 		t := v.Type()
 
-		name := t.String()
-		name = s.typeReplacer.Replace(name)
-		s.write(name + "{")
+		s.write(s.nameOf(t) + "{")
 
-		for _, key := range v.MapKeys() {
+		keys := v.MapKeys()
+		sortMapKeys(keys, s.renderers)
+
+		for _, key := range keys {
 			// Render key
 			s.write("\n" + innerTabs)
-			s.snap(key.Interface(), indent+1, true)
+			s.snap(key, indent+1, true)
 			s.write(": ")
 
 			// Render value
-			element := v.MapIndex(key)
-			s.snap(element.Interface(), indent+1, true)
+			s.snap(v.MapIndex(key), indent+1, true)
 			s.write(",")
 		}
 
@@ -200,14 +356,66 @@ func (s *snapper) snap(i any, indent int, omitStructName bool) {
 
 	// Pointers
 	case reflect.Pointer:
-		// We know it's not nil because we checked earlier
+		if v.IsNil() {
+			s.write("nil")
+			return
+		}
+
+		addr := v.Pointer()
+		if n, ok := s.shared[addr]; ok {
+			if addr == s.defining {
+				fmt.Fprintf(s.buf, "/* cycle: &%s#%d */", s.nameOf(v.Elem().Type()), n)
+				return
+			}
+
+			fmt.Fprintf(s.buf, "snap%d", n)
+			return
+		}
+
 		s.write("&")
-		s.snap(v.Elem().Interface(), indent, false) // Might be able to omit struct name here?
+		s.snap(v.Elem(), indent, false) // Might be able to omit struct name here?
 	}
 
 	return
 }
 
+// sortMapKeys orders keys so map snapshots are stable across runs despite
+// Go's randomized map iteration. Primitive keys sort by their natural
+// order; anything else (structs, interfaces, ...) falls back to sorting
+// by its own rendered form, which is stable even if not meaningful to a
+// reader.
+func sortMapKeys(keys []reflect.Value, renderers map[reflect.Type]func(any) string) {
+	sort.Slice(keys, func(i, j int) bool {
+		return lessMapKey(keys[i], keys[j], renderers)
+	})
+}
+
+func lessMapKey(a, b reflect.Value, renderers map[reflect.Type]func(any) string) bool {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() < b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float()
+	case reflect.String:
+		return a.String() < b.String()
+	case reflect.Bool:
+		return !a.Bool() && b.Bool()
+	default:
+		return renderSortKey(a, renderers) < renderSortKey(b, renderers)
+	}
+}
+
+// renderSortKey renders v the same way the main snapshot would, custom
+// renderers included, for use as a stable sort key when v's kind has no
+// natural order of its own.
+func renderSortKey(v reflect.Value, renderers map[reflect.Type]func(any) string) string {
+	tmp := &snapper{buf: new(bytes.Buffer), typeName: func(t reflect.Type) string { return t.String() }, renderers: renderers}
+	tmp.snap(v, 0, true)
+	return tmp.buf.String()
+}
+
 func cleanEmptyInterface(in string, useAny bool) string {
 	if useAny {
 		return strings.ReplaceAll(in, "interface {}", "any")
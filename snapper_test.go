@@ -151,6 +151,32 @@ func TestSnap(t *testing.T) {
 		Bar: "a",
 		Baz: 2,
 	},
+}`,
+		},
+		{
+			name:  "map with multiple int keys",
+			input: map[int]string{3: "c", 1: "a", 2: "b"},
+			expect: `map[int]string{
+	1: "a",
+	2: "b",
+	3: "c",
+}`,
+		},
+		{
+			name: "map with struct keys",
+			input: map[SampleStruct]int{
+				{Bar: "z", Baz: 2}: 2,
+				{Bar: "a", Baz: 1}: 1,
+			},
+			expect: `map[SampleStruct]int{
+	{
+		Bar: "a",
+		Baz: 1,
+	}: 1,
+	{
+		Bar: "z",
+		Baz: 2,
+	}: 2,
 }`,
 		},
 		{
@@ -176,7 +202,7 @@ func TestSnap(t *testing.T) {
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := snapper.Ssnap(tt.input, map[string]string{"snapper_test": ""})
+			actual := snapper.Ssnap(tt.input)
 			assert.Equal(t, tt.expect, actual)
 		})
 	}
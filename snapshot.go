@@ -0,0 +1,235 @@
+package snapper
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// updateFlag is only registered in test binaries (see init below), since
+// snapper is also imported by non-test code for Snap/Ssnap/Fsnap, and
+// unconditionally defining a global "-update" flag would risk "flag
+// redefined" panics in any application that happens to already have one.
+var updateFlag *bool
+
+func init() {
+	if testing.Testing() {
+		updateFlag = flag.Bool("update", false, "rewrite snapper snapshots instead of comparing against them")
+	}
+}
+
+// shouldUpdate reports whether snapshots should be (re)written instead of
+// compared, via either the -update flag or the UPDATE_SNAPSHOTS=1 env var.
+func shouldUpdate() bool {
+	return (updateFlag != nil && *updateFlag) || os.Getenv("UPDATE_SNAPSHOTS") == "1"
+}
+
+// snapshotFile is the in-memory view of a single
+// testdata/__snapshots__/<TestName>.snap file: its entries in on-disk
+// order, plus which ones have been touched during the current run.
+type snapshotFile struct {
+	mu      sync.Mutex
+	path    string
+	order   []string
+	entries map[string]string
+	matched map[string]bool
+	dirty   bool
+}
+
+var (
+	filesMu sync.Mutex
+	files   = map[string]*snapshotFile{}
+
+	countersMu sync.Mutex
+	counters   = map[*testing.T]int{}
+)
+
+// MatchSnapshot renders value the same way Ssnap does and compares it
+// against the snapshot stored for the current test, failing t with a
+// diff on mismatch. The first time a snapshot is seen (or whenever
+// snapshots are being updated, see below) the rendered value is written
+// instead of compared.
+//
+// Snapshots live in testdata/__snapshots__/<TestName>.snap. A test may
+// call MatchSnapshot more than once; snapshots are indexed by call order
+// unless WithName gives them an explicit name. Set UPDATE_SNAPSHOTS=1, or
+// run `go test -update`, to rewrite snapshots instead of checking them.
+// If a test finishes without matching every snapshot already stored for
+// it, the test fails listing the orphaned entries.
+func MatchSnapshot(t *testing.T, value any, opts ...Option) {
+	t.Helper()
+
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	name := cfg.name
+	if name == "" {
+		countersMu.Lock()
+		counters[t]++
+		name = strconv.Itoa(counters[t])
+		countersMu.Unlock()
+	}
+
+	path := snapshotPath(t)
+
+	filesMu.Lock()
+	f, ok := files[path]
+	if !ok {
+		var err error
+		f, err = loadSnapshotFile(path)
+		if err != nil {
+			filesMu.Unlock()
+			t.Fatalf("reading snapshot file %s: %v", path, err)
+		}
+		files[path] = f
+		t.Cleanup(func() { finalizeSnapshotFile(t, f) })
+	}
+	filesMu.Unlock()
+
+	rendered := render(value, true, 1, opts)
+
+	f.mu.Lock()
+	mismatch := recordSnapshot(f, name, rendered, shouldUpdate())
+	f.mu.Unlock()
+
+	if mismatch != "" {
+		t.Errorf("snapshot %q mismatch (-want +got):\n%s", name, mismatch)
+	}
+}
+
+// recordSnapshot updates f with a new observation of the snapshot called
+// name, returning a non-empty diff if it conflicts with a previously
+// stored value and update is false. The caller must hold f.mu.
+func recordSnapshot(f *snapshotFile, name, rendered string, update bool) string {
+	existing, had := f.entries[name]
+
+	switch {
+	case !had:
+		f.entries[name] = rendered
+		f.order = append(f.order, name)
+		f.dirty = true
+	case update:
+		if existing != rendered {
+			f.entries[name] = rendered
+			f.dirty = true
+		}
+	case existing != rendered:
+		f.matched[name] = true
+		return unifiedDiff(existing, rendered)
+	}
+
+	f.matched[name] = true
+	return ""
+}
+
+func finalizeSnapshotFile(t *testing.T, f *snapshotFile) {
+	t.Helper()
+
+	f.mu.Lock()
+	stale := staleEntries(f)
+	dirty := f.dirty
+	f.mu.Unlock()
+
+	for _, name := range stale {
+		t.Errorf("snapshot %q was never matched during this run; remove it from %s", name, f.path)
+	}
+
+	if !dirty {
+		return
+	}
+
+	if err := writeSnapshotFile(f); err != nil {
+		t.Errorf("writing snapshot file %s: %v", f.path, err)
+	}
+}
+
+// staleEntries returns the names of snapshots stored in f that were never
+// matched during the current run. The caller must hold f.mu.
+func staleEntries(f *snapshotFile) []string {
+	var stale []string
+	for _, name := range f.order {
+		if !f.matched[name] {
+			stale = append(stale, name)
+		}
+	}
+	return stale
+}
+
+// snapshotPath returns the snapshot file for t, flattening subtest names
+// so the result is always a single valid path component.
+func snapshotPath(t *testing.T) string {
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	return filepath.Join("testdata", "__snapshots__", name+".snap")
+}
+
+var snapshotEntryHeader = regexp.MustCompile(`^\[\[(.*)\]\]$`)
+
+const snapshotFileHeader = "// Code generated by snapper. DO NOT EDIT BY HAND.\n" +
+	"// Run `go test -update` or set UPDATE_SNAPSHOTS=1 to regenerate.\n\n"
+
+func loadSnapshotFile(path string) (*snapshotFile, error) {
+	f := &snapshotFile{
+		path:    path,
+		entries: map[string]string{},
+		matched: map[string]bool{},
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var name string
+	var body strings.Builder
+
+	flush := func() {
+		if name == "" {
+			return
+		}
+		f.entries[name] = strings.TrimRight(body.String(), "\n")
+		f.order = append(f.order, name)
+		body.Reset()
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if m := snapshotEntryHeader.FindStringSubmatch(line); m != nil {
+			flush()
+			name = m[1]
+			continue
+		}
+		if name == "" {
+			continue // header comment / blank lines before the first entry
+		}
+		body.WriteString(line)
+		body.WriteString("\n")
+	}
+	flush()
+
+	return f, nil
+}
+
+func writeSnapshotFile(f *snapshotFile) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(snapshotFileHeader)
+	for _, name := range f.order {
+		fmt.Fprintf(&b, "[[%s]]\n%s\n\n", name, f.entries[name])
+	}
+
+	return os.WriteFile(f.path, []byte(b.String()), 0o644)
+}
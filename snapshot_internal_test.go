@@ -0,0 +1,119 @@
+package snapper
+
+import (
+	"testing"
+)
+
+func newTestSnapshotFile() *snapshotFile {
+	return &snapshotFile{
+		path:    "testdata/__snapshots__/fake.snap",
+		entries: map[string]string{},
+		matched: map[string]bool{},
+	}
+}
+
+func TestRecordSnapshot_FirstObservationWrites(t *testing.T) {
+	f := newTestSnapshotFile()
+
+	diff := recordSnapshot(f, "1", "hello", false)
+
+	if diff != "" {
+		t.Fatalf("expected no diff on first observation, got %q", diff)
+	}
+	if f.entries["1"] != "hello" || !f.dirty {
+		t.Fatalf("expected entry to be written and file marked dirty, got %+v", f)
+	}
+}
+
+func TestRecordSnapshot_MatchIsClean(t *testing.T) {
+	f := newTestSnapshotFile()
+	f.entries["1"] = "hello"
+	f.order = []string{"1"}
+
+	diff := recordSnapshot(f, "1", "hello", false)
+
+	if diff != "" {
+		t.Fatalf("expected no diff on match, got %q", diff)
+	}
+	if f.dirty {
+		t.Fatalf("matching an existing entry should not mark the file dirty")
+	}
+	if !f.matched["1"] {
+		t.Fatalf("expected entry to be recorded as matched")
+	}
+}
+
+func TestRecordSnapshot_MismatchReturnsDiff(t *testing.T) {
+	f := newTestSnapshotFile()
+	f.entries["1"] = "hello"
+	f.order = []string{"1"}
+
+	diff := recordSnapshot(f, "1", "goodbye", false)
+
+	if diff == "" {
+		t.Fatalf("expected a diff on mismatch")
+	}
+	if f.entries["1"] != "hello" {
+		t.Fatalf("a mismatch without update should not overwrite the stored entry")
+	}
+	if !f.matched["1"] {
+		t.Fatalf("a compared entry should be marked matched even on mismatch")
+	}
+}
+
+func TestRecordSnapshot_UpdateOverwritesMismatch(t *testing.T) {
+	f := newTestSnapshotFile()
+	f.entries["1"] = "hello"
+	f.order = []string{"1"}
+
+	diff := recordSnapshot(f, "1", "goodbye", true)
+
+	if diff != "" {
+		t.Fatalf("update mode should never report a diff, got %q", diff)
+	}
+	if f.entries["1"] != "goodbye" || !f.dirty {
+		t.Fatalf("expected entry to be rewritten and file marked dirty, got %+v", f)
+	}
+}
+
+func TestStaleEntries(t *testing.T) {
+	f := newTestSnapshotFile()
+	f.order = []string{"1", "2", "3"}
+	f.matched["1"] = true
+	f.matched["3"] = true
+
+	stale := staleEntries(f)
+
+	if len(stale) != 1 || stale[0] != "2" {
+		t.Fatalf("expected only %q to be stale, got %v", "2", stale)
+	}
+}
+
+func TestSnapshotFileRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/testdata/__snapshots__/TestFoo.snap"
+
+	f, err := loadSnapshotFile(path)
+	if err != nil {
+		t.Fatalf("loading a missing snapshot file should not error: %v", err)
+	}
+
+	recordSnapshot(f, "1", "alpha", false)
+	recordSnapshot(f, "named", "beta", false)
+
+	if err := writeSnapshotFile(f); err != nil {
+		t.Fatalf("writeSnapshotFile: %v", err)
+	}
+
+	reloaded, err := loadSnapshotFile(path)
+	if err != nil {
+		t.Fatalf("loadSnapshotFile: %v", err)
+	}
+
+	if reloaded.entries["1"] != "alpha" || reloaded.entries["named"] != "beta" {
+		t.Fatalf("round-tripped entries don't match, got %+v", reloaded.entries)
+	}
+	if len(reloaded.order) != 2 || reloaded.order[0] != "1" || reloaded.order[1] != "named" {
+		t.Fatalf("expected entry order to be preserved, got %v", reloaded.order)
+	}
+}
@@ -0,0 +1,106 @@
+package snapper_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gregoryjjb/snapper"
+)
+
+// withTempCwd chdirs into a fresh temp directory for the duration of the
+// test, so MatchSnapshot's testdata/__snapshots__ writes don't touch the
+// real repo.
+func withTempCwd(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+}
+
+// seedSnapshot writes a snapshot file as if a prior run had already
+// produced it, so a test can exercise the compare path without relying on
+// a real second process invocation.
+func seedSnapshot(t *testing.T, name string, entries map[string]string, order []string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "__snapshots__", name+".snap")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+	var content string
+	for _, key := range order {
+		content += "[[" + key + "]]\n" + entries[key] + "\n\n"
+	}
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestMatchSnapshot_FirstRunWrites(t *testing.T) {
+	withTempCwd(t)
+
+	t.Run("sub", func(t *testing.T) {
+		snapper.MatchSnapshot(t, SampleStruct{Bar: "a", Baz: 1})
+	})
+
+	data, err := os.ReadFile(filepath.Join("testdata", "__snapshots__", "TestMatchSnapshot_FirstRunWrites_sub.snap"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `Bar: "a"`)
+	assert.Contains(t, string(data), `[[1]]`)
+}
+
+func TestMatchSnapshot_MatchesStoredSnapshot(t *testing.T) {
+	withTempCwd(t)
+
+	seedSnapshot(t, "TestMatchSnapshot_MatchesStoredSnapshot_sub", map[string]string{
+		"1": "SampleStruct{\n\tBar: \"a\",\n\tBaz: 1,\n}",
+	}, []string{"1"})
+
+	ok := t.Run("sub", func(t *testing.T) {
+		snapper.MatchSnapshot(t, SampleStruct{Bar: "a", Baz: 1})
+	})
+
+	assert.True(t, ok, "matching value against a stored snapshot should pass")
+}
+
+func TestMatchSnapshot_MultipleAndNamed(t *testing.T) {
+	withTempCwd(t)
+
+	t.Run("multi", func(t *testing.T) {
+		snapper.MatchSnapshot(t, 1)
+		snapper.MatchSnapshot(t, 2, snapper.WithName("second"))
+		snapper.MatchSnapshot(t, 3)
+	})
+
+	data, err := os.ReadFile(filepath.Join("testdata", "__snapshots__", "TestMatchSnapshot_MultipleAndNamed_multi.snap"))
+	require.NoError(t, err)
+
+	content := string(data)
+	assert.Contains(t, content, "[[1]]\n1")
+	assert.Contains(t, content, "[[second]]\n2")
+	assert.Contains(t, content, "[[2]]\n3")
+}
+
+func TestMatchSnapshot_UpdateEnvVarRewrites(t *testing.T) {
+	withTempCwd(t)
+
+	seedSnapshot(t, "TestMatchSnapshot_UpdateEnvVarRewrites_sub", map[string]string{
+		"1": "1",
+	}, []string{"1"})
+
+	t.Setenv("UPDATE_SNAPSHOTS", "1")
+
+	ok := t.Run("sub", func(t *testing.T) {
+		snapper.MatchSnapshot(t, 2)
+	})
+	require.True(t, ok)
+
+	data, err := os.ReadFile(filepath.Join("testdata", "__snapshots__", "TestMatchSnapshot_UpdateEnvVarRewrites_sub.snap"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "[[1]]\n2")
+}
@@ -0,0 +1,94 @@
+package snapper
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+// WithStdRenderers opts a single call into snapper's built-in renderers
+// for time.Time, time.Duration, *big.Int, and *big.Rat, none of which
+// round-trip usefully through the default reflect-based rendering (their
+// interesting state lives in unexported fields).
+func WithStdRenderers() Option {
+	return func(c *config) {
+		for t, render := range stdRenderers {
+			c.setRenderer(t, render)
+		}
+	}
+}
+
+var stdRenderers = map[reflect.Type]func(any) string{
+	reflect.TypeOf(time.Time{}):      func(v any) string { return renderTime(v.(time.Time)) },
+	reflect.TypeOf(time.Duration(0)): func(v any) string { return renderDuration(v.(time.Duration)) },
+	reflect.TypeOf((*big.Int)(nil)):  func(v any) string { return renderBigInt(v.(*big.Int)) },
+	reflect.TypeOf((*big.Rat)(nil)):  func(v any) string { return renderBigRat(v.(*big.Rat)) },
+}
+
+// renderTime renders t as a time.Date call, normalized to UTC so the
+// same instant always produces the same snapshot regardless of the
+// machine's local timezone.
+func renderTime(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf(
+		"time.Date(%d, %d, %d, %d, %d, %d, %d, time.UTC)",
+		t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(),
+	)
+}
+
+// durationUnits are tried largest-first so renderDuration picks the
+// coarsest unit that evenly divides d.
+var durationUnits = []struct {
+	unit time.Duration
+	name string
+}{
+	{time.Hour, "time.Hour"},
+	{time.Minute, "time.Minute"},
+	{time.Second, "time.Second"},
+	{time.Millisecond, "time.Millisecond"},
+	{time.Microsecond, "time.Microsecond"},
+	{time.Nanosecond, "time.Nanosecond"},
+}
+
+// renderDuration renders d as "N * time.Unit" using the coarsest unit
+// that divides it evenly, e.g. "5 * time.Second".
+func renderDuration(d time.Duration) string {
+	for _, u := range durationUnits {
+		if d%u.unit != 0 {
+			continue
+		}
+
+		n := d / u.unit
+		if n == 1 {
+			return u.name
+		}
+		return fmt.Sprintf("%d * %s", n, u.name)
+	}
+
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
+// renderBigInt renders v as big.NewInt(n) when it fits an int64, falling
+// back to a self-contained parse expression for larger values.
+func renderBigInt(v *big.Int) string {
+	if v == nil {
+		return "nil"
+	}
+	if v.IsInt64() {
+		return fmt.Sprintf("big.NewInt(%d)", v.Int64())
+	}
+	return fmt.Sprintf("func() *big.Int { n, _ := new(big.Int).SetString(%q, 10); return n }()", v.String())
+}
+
+// renderBigRat renders v as big.NewRat(num, denom) when both fit an
+// int64, falling back to a self-contained parse expression otherwise.
+func renderBigRat(v *big.Rat) string {
+	if v == nil {
+		return "nil"
+	}
+	if v.Num().IsInt64() && v.Denom().IsInt64() {
+		return fmt.Sprintf("big.NewRat(%d, %d)", v.Num().Int64(), v.Denom().Int64())
+	}
+	return fmt.Sprintf("func() *big.Rat { r, _ := new(big.Rat).SetString(%q); return r }()", v.RatString())
+}